@@ -0,0 +1,50 @@
+// Package metrics defines the abstractions used to record HTTP metrics so
+// the middleware implementations don't need to be coupled to a specific
+// metrics backend (Prometheus, OpenTelemetry, statsd...).
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// Recorder knows how to record and measure the metrics. This is how the
+// metrics will be recorded by the different supported backends.
+type Recorder interface {
+	// ObserveHTTPRequestDuration measures the duration of an HTTP request.
+	ObserveHTTPRequestDuration(ctx context.Context, p HTTPReqProperties, duration time.Duration)
+	// ObserveHTTPRequestSize measures the size of an HTTP request in bytes.
+	ObserveHTTPRequestSize(ctx context.Context, p HTTPReqProperties, sizeBytes int64)
+	// ObserveHTTPResponseSize measures the size of an HTTP response in bytes.
+	ObserveHTTPResponseSize(ctx context.Context, p HTTPReqProperties, sizeBytes int64)
+	// AddInflightRequests increments and decrements the number of inflight requests being
+	// processed.
+	AddInflightRequests(ctx context.Context, p HTTPProperties, quantity int)
+	// AddRequestCount increments the total number of HTTP requests served.
+	AddRequestCount(ctx context.Context, p HTTPReqProperties)
+	// AddErrorCount increments the total number of HTTP requests that ended in error.
+	AddErrorCount(ctx context.Context, p HTTPReqProperties)
+}
+
+// HTTPProperties are the HTTP properties required to measure the inflight requests metrics.
+type HTTPProperties struct {
+	// Service is the service that is being measured.
+	Service string
+	// ID is the id that identifies the HTTP handler measured, normally this is a path, eg: /api/customers.
+	ID string
+}
+
+// HTTPReqProperties are the properties required to measure the HTTP request/response metrics.
+type HTTPReqProperties struct {
+	// Service is the service that is being measured.
+	Service string
+	// ID is the id that identifies the HTTP request measured, normally this is a path, eg: /api/customers.
+	ID string
+	// Method is the method of the HTTP request.
+	Method string
+	// Code is the response code of the HTTP request.
+	Code string
+	// Body is the raw body of the HTTP request, it's handed to the Prometheus recorder's
+	// CustomLabels reporter. The middleware in pkg/middleware doesn't populate it.
+	Body []byte
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MultiRecorder is a Recorder that fans out every call to a set of Recorders concurrently.
+// It's useful to emit the same metrics to more than one backend (eg: Prometheus and
+// OpenTelemetry) without changing the middleware that uses the Recorder.
+//
+// A slow or failing Recorder doesn't prevent the others from recording: every backend is
+// called concurrently and MultiRecorder waits for all of them to finish before returning.
+type MultiRecorder struct {
+	recorders []Recorder
+}
+
+// Tee returns a Recorder that fans out every recorded metric to all the given recorders.
+func Tee(r ...Recorder) Recorder {
+	return MultiRecorder{recorders: r}
+}
+
+func (m MultiRecorder) fanOut(f func(r Recorder)) {
+	var wg sync.WaitGroup
+	wg.Add(len(m.recorders))
+	for _, r := range m.recorders {
+		r := r
+		go func() {
+			defer wg.Done()
+			// A panicking backend must not take down the others or the caller.
+			defer func() { recover() }()
+			f(r)
+		}()
+	}
+	wg.Wait()
+}
+
+// ObserveHTTPRequestDuration implements Recorder.
+func (m MultiRecorder) ObserveHTTPRequestDuration(ctx context.Context, p HTTPReqProperties, duration time.Duration) {
+	m.fanOut(func(r Recorder) { r.ObserveHTTPRequestDuration(ctx, p, duration) })
+}
+
+// ObserveHTTPRequestSize implements Recorder.
+func (m MultiRecorder) ObserveHTTPRequestSize(ctx context.Context, p HTTPReqProperties, sizeBytes int64) {
+	m.fanOut(func(r Recorder) { r.ObserveHTTPRequestSize(ctx, p, sizeBytes) })
+}
+
+// ObserveHTTPResponseSize implements Recorder.
+func (m MultiRecorder) ObserveHTTPResponseSize(ctx context.Context, p HTTPReqProperties, sizeBytes int64) {
+	m.fanOut(func(r Recorder) { r.ObserveHTTPResponseSize(ctx, p, sizeBytes) })
+}
+
+// AddInflightRequests implements Recorder.
+func (m MultiRecorder) AddInflightRequests(ctx context.Context, p HTTPProperties, quantity int) {
+	m.fanOut(func(r Recorder) { r.AddInflightRequests(ctx, p, quantity) })
+}
+
+// AddRequestCount implements Recorder.
+func (m MultiRecorder) AddRequestCount(ctx context.Context, p HTTPReqProperties) {
+	m.fanOut(func(r Recorder) { r.AddRequestCount(ctx, p) })
+}
+
+// AddErrorCount implements Recorder.
+func (m MultiRecorder) AddErrorCount(ctx context.Context, p HTTPReqProperties) {
+	m.fanOut(func(r Recorder) { r.AddErrorCount(ctx, p) })
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorderFunc struct {
+	onAddRequestCount func()
+}
+
+func (f recorderFunc) ObserveHTTPRequestDuration(context.Context, HTTPReqProperties, time.Duration) {}
+func (f recorderFunc) ObserveHTTPRequestSize(context.Context, HTTPReqProperties, int64)             {}
+func (f recorderFunc) ObserveHTTPResponseSize(context.Context, HTTPReqProperties, int64)            {}
+func (f recorderFunc) AddInflightRequests(context.Context, HTTPProperties, int)                     {}
+func (f recorderFunc) AddRequestCount(context.Context, HTTPReqProperties) {
+	if f.onAddRequestCount != nil {
+		f.onAddRequestCount()
+	}
+}
+func (f recorderFunc) AddErrorCount(context.Context, HTTPReqProperties) {}
+
+func TestTeeFansOutToEveryRecorder(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+
+	r := Tee(
+		recorderFunc{onAddRequestCount: func() { mu.Lock(); calls++; mu.Unlock() }},
+		recorderFunc{onAddRequestCount: func() { mu.Lock(); calls++; mu.Unlock() }},
+		recorderFunc{onAddRequestCount: func() { mu.Lock(); calls++; mu.Unlock() }},
+	)
+
+	r.AddRequestCount(context.Background(), HTTPReqProperties{})
+
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
+
+func TestTeeIsolatesAPanickingRecorder(t *testing.T) {
+	var called bool
+
+	r := Tee(
+		recorderFunc{onAddRequestCount: func() { panic("boom") }},
+		recorderFunc{onAddRequestCount: func() { called = true }},
+	)
+
+	r.AddRequestCount(context.Background(), HTTPReqProperties{})
+
+	if !called {
+		t.Fatal("a panicking recorder must not prevent the others from recording")
+	}
+}
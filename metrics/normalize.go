@@ -0,0 +1,52 @@
+package metrics
+
+// LabelNormalizer is a hook that rewrites a HTTPReqProperties before it's turned into metric
+// labels. It's applied right before recording a measurement, so it can be used to curb label
+// cardinality, eg: collapsing status codes into classes or turning raw URL paths into route
+// templates.
+type LabelNormalizer func(p HTTPReqProperties) HTTPReqProperties
+
+// StatusCodeClassNormalizer is a LabelNormalizer that collapses the Code label into its status
+// code class (`1xx`, `2xx`, `3xx`, `4xx`, `5xx`), leaving non-numeric or out of range codes as
+// `unknown`. This is a common recommendation to avoid a metric series per status code.
+func StatusCodeClassNormalizer(p HTTPReqProperties) HTTPReqProperties {
+	p.Code = statusCodeClass(p.Code)
+	return p
+}
+
+func statusCodeClass(code string) string {
+	if len(code) != 3 || code[1] < '0' || code[1] > '9' || code[2] < '0' || code[2] > '9' {
+		return "unknown"
+	}
+
+	switch code[0] {
+	case '1':
+		return "1xx"
+	case '2':
+		return "2xx"
+	case '3':
+		return "3xx"
+	case '4':
+		return "4xx"
+	case '5':
+		return "5xx"
+	default:
+		return "unknown"
+	}
+}
+
+// HandlerIDTemplateNormalizer returns a LabelNormalizer that rewrites the ID label from a raw
+// URL path (eg: `/api/customers/42`) into the route template it matches (eg:
+// `/api/customers/:id`), using patterns as the set of known route templates. Paths that don't
+// match any pattern are left untouched. This is meant for apps that pass `r.URL.Path` as the
+// handler ID and would otherwise create one metric series per unique path.
+func HandlerIDTemplateNormalizer(patterns ...string) LabelNormalizer {
+	m := newPathMatcher(patterns)
+
+	return func(p HTTPReqProperties) HTTPReqProperties {
+		if tpl, ok := m.match(p.ID); ok {
+			p.ID = tpl
+		}
+		return p
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import "testing"
+
+func TestStatusCodeClassNormalizer(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		want string
+	}{
+		"1xx":                {code: "100", want: "1xx"},
+		"2xx":                {code: "200", want: "2xx"},
+		"3xx":                {code: "304", want: "3xx"},
+		"4xx":                {code: "404", want: "4xx"},
+		"5xx":                {code: "503", want: "5xx"},
+		"empty is unknown":   {code: "", want: "unknown"},
+		"non numeric class":  {code: "abc", want: "unknown"},
+		"out of range class": {code: "900", want: "unknown"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := StatusCodeClassNormalizer(HTTPReqProperties{Code: test.code})
+			if got.Code != test.want {
+				t.Fatalf("got code %q, want %q", got.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestStatusCodeClassNormalizerPreservesOtherFields(t *testing.T) {
+	p := HTTPReqProperties{Service: "svc", ID: "/api", Method: "GET", Code: "200"}
+	got := StatusCodeClassNormalizer(p)
+
+	if got.Service != p.Service || got.ID != p.ID || got.Method != p.Method {
+		t.Fatalf("normalizer must only touch Code, got %+v", got)
+	}
+}
+
+func TestHandlerIDTemplateNormalizer(t *testing.T) {
+	normalize := HandlerIDTemplateNormalizer("/api/customers/:id")
+
+	got := normalize(HTTPReqProperties{ID: "/api/customers/42"})
+	if got.ID != "/api/customers/:id" {
+		t.Fatalf("got ID %q, want %q", got.ID, "/api/customers/:id")
+	}
+
+	got = normalize(HTTPReqProperties{ID: "/api/unknown"})
+	if got.ID != "/api/unknown" {
+		t.Fatalf("unmatched path must be left untouched, got %q", got.ID)
+	}
+}
@@ -0,0 +1,125 @@
+// Package otel implements metrics.Recorder on top of the OpenTelemetry metrics API, so
+// go-http-metrics can be used to feed an OpenTelemetry pipeline instead of, or together
+// with (see metrics.Tee), Prometheus.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/slok/go-http-metrics/metrics"
+)
+
+// Config has the dependencies and values of the recorder.
+type Config struct {
+	// Meter is the OpenTelemetry meter used to create the instruments, by default it will
+	// use a no-op meter provider with the "github.com/slok/go-http-metrics" name, so
+	// nothing is exported unless a Meter is set.
+	Meter metric.Meter
+}
+
+func (c *Config) defaults() {
+	if c.Meter == nil {
+		c.Meter = noop.NewMeterProvider().Meter("github.com/slok/go-http-metrics")
+	}
+}
+
+type recorder struct {
+	httpRequestDurHistogram   metric.Float64Histogram
+	httpRequestSizeHistogram  metric.Int64Histogram
+	httpResponseSizeHistogram metric.Int64Histogram
+	httpRequestsInflight      metric.Int64UpDownCounter
+	httpRequestsTotal         metric.Int64Counter
+	httpRequestErrorsTotal    metric.Int64Counter
+}
+
+// NewRecorder returns a new metrics recorder that implements metrics.Recorder using
+// OpenTelemetry as the backend.
+func NewRecorder(cfg Config) (metrics.Recorder, error) {
+	cfg.defaults()
+
+	httpRequestDurHistogram, err := cfg.Meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("The latency of the HTTP requests."), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequestSizeHistogram, err := cfg.Meter.Int64Histogram("http.server.request.size",
+		metric.WithDescription("The size of the HTTP requests."), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	httpResponseSizeHistogram, err := cfg.Meter.Int64Histogram("http.server.response.size",
+		metric.WithDescription("The size of the HTTP responses."), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequestsInflight, err := cfg.Meter.Int64UpDownCounter("http.server.requests.inflight",
+		metric.WithDescription("The number of inflight requests being handled at the same time."))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequestsTotal, err := cfg.Meter.Int64Counter("http.server.requests.total",
+		metric.WithDescription("The total number of HTTP requests handled."))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequestErrorsTotal, err := cfg.Meter.Int64Counter("http.server.request.errors.total",
+		metric.WithDescription("The total number of HTTP requests that ended in a server error."))
+	if err != nil {
+		return nil, err
+	}
+
+	return recorder{
+		httpRequestDurHistogram:   httpRequestDurHistogram,
+		httpRequestSizeHistogram:  httpRequestSizeHistogram,
+		httpResponseSizeHistogram: httpResponseSizeHistogram,
+		httpRequestsInflight:      httpRequestsInflight,
+		httpRequestsTotal:         httpRequestsTotal,
+		httpRequestErrorsTotal:    httpRequestErrorsTotal,
+	}, nil
+}
+
+func attrs(p metrics.HTTPReqProperties) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("service", p.Service),
+		attribute.String("handler", p.ID),
+		attribute.String("method", p.Method),
+		attribute.String("code", p.Code),
+	}
+}
+
+func (r recorder) ObserveHTTPRequestDuration(ctx context.Context, p metrics.HTTPReqProperties, duration time.Duration) {
+	r.httpRequestDurHistogram.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs(p)...))
+}
+
+func (r recorder) ObserveHTTPRequestSize(ctx context.Context, p metrics.HTTPReqProperties, sizeBytes int64) {
+	r.httpRequestSizeHistogram.Record(ctx, sizeBytes, metric.WithAttributes(attrs(p)...))
+}
+
+func (r recorder) ObserveHTTPResponseSize(ctx context.Context, p metrics.HTTPReqProperties, sizeBytes int64) {
+	r.httpResponseSizeHistogram.Record(ctx, sizeBytes, metric.WithAttributes(attrs(p)...))
+}
+
+func (r recorder) AddInflightRequests(ctx context.Context, p metrics.HTTPProperties, quantity int) {
+	r.httpRequestsInflight.Add(ctx, int64(quantity), metric.WithAttributes(
+		attribute.String("service", p.Service),
+		attribute.String("handler", p.ID),
+	))
+}
+
+func (r recorder) AddRequestCount(ctx context.Context, p metrics.HTTPReqProperties) {
+	r.httpRequestsTotal.Add(ctx, 1, metric.WithAttributes(attrs(p)...))
+}
+
+func (r recorder) AddErrorCount(ctx context.Context, p metrics.HTTPReqProperties) {
+	r.httpRequestErrorsTotal.Add(ctx, 1, metric.WithAttributes(attrs(p)...))
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import "strings"
+
+// pathMatcher is a radix tree of URL path segments used to match a raw request path against a
+// set of route templates (eg: `/api/customers/:id`) without paying for a regexp per pattern.
+// Segments starting with `:` are treated as wildcards that match any single segment.
+type pathMatcher struct {
+	root *pathNode
+}
+
+type pathNode struct {
+	children map[string]*pathNode
+	wildcard *pathNode
+	template string
+	isLeaf   bool
+}
+
+func newPathMatcher(patterns []string) *pathMatcher {
+	m := &pathMatcher{root: &pathNode{children: map[string]*pathNode{}}}
+	for _, pattern := range patterns {
+		m.add(pattern)
+	}
+	return m
+}
+
+func (m *pathMatcher) add(pattern string) {
+	node := m.root
+	for _, seg := range splitPath(pattern) {
+		if isWildcardSegment(seg) {
+			if node.wildcard == nil {
+				node.wildcard = &pathNode{children: map[string]*pathNode{}}
+			}
+			node = node.wildcard
+			continue
+		}
+
+		child, ok := node.children[seg]
+		if !ok {
+			child = &pathNode{children: map[string]*pathNode{}}
+			node.children[seg] = child
+		}
+		node = child
+	}
+
+	node.isLeaf = true
+	node.template = pattern
+}
+
+// match returns the route template that path matches, preferring literal segments over
+// wildcards at every level, and whether a match was found. A literal branch that matches the
+// current segment but dead-ends further down doesn't fail the whole match: it backtracks and
+// falls back to the wildcard branch at that level.
+func (m *pathMatcher) match(path string) (string, bool) {
+	return m.root.match(splitPath(path))
+}
+
+func (n *pathNode) match(segs []string) (string, bool) {
+	if len(segs) == 0 {
+		if n.isLeaf {
+			return n.template, true
+		}
+		return "", false
+	}
+
+	seg, rest := segs[0], segs[1:]
+
+	if child, ok := n.children[seg]; ok {
+		if tpl, ok := child.match(rest); ok {
+			return tpl, true
+		}
+	}
+
+	if n.wildcard != nil {
+		if tpl, ok := n.wildcard.match(rest); ok {
+			return tpl, true
+		}
+	}
+
+	return "", false
+}
+
+func isWildcardSegment(seg string) bool {
+	return strings.HasPrefix(seg, ":") || (strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"))
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import "testing"
+
+func TestPathMatcherMatch(t *testing.T) {
+	tests := map[string]struct {
+		patterns []string
+		path     string
+		wantTpl  string
+		wantOk   bool
+	}{
+		"exact path matches its own pattern": {
+			patterns: []string{"/api/health"},
+			path:     "/api/health",
+			wantTpl:  "/api/health",
+			wantOk:   true,
+		},
+		"wildcard segment matches any value": {
+			patterns: []string{"/api/customers/:id"},
+			path:     "/api/customers/42",
+			wantTpl:  "/api/customers/:id",
+			wantOk:   true,
+		},
+		"brace wildcard segment matches any value": {
+			patterns: []string{"/api/customers/{id}"},
+			path:     "/api/customers/42",
+			wantTpl:  "/api/customers/{id}",
+			wantOk:   true,
+		},
+		"literal segments are preferred over wildcards": {
+			patterns: []string{"/api/customers/:id", "/api/customers/new"},
+			path:     "/api/customers/new",
+			wantTpl:  "/api/customers/new",
+			wantOk:   true,
+		},
+		"nested wildcards match multiple dynamic segments": {
+			patterns: []string{"/api/customers/:id/orders/:orderID"},
+			path:     "/api/customers/42/orders/7",
+			wantTpl:  "/api/customers/:id/orders/:orderID",
+			wantOk:   true,
+		},
+		"path with no matching pattern is left alone": {
+			patterns: []string{"/api/customers/:id"},
+			path:     "/api/products/42",
+			wantTpl:  "",
+			wantOk:   false,
+		},
+		"path shorter than the pattern doesn't match": {
+			patterns: []string{"/api/customers/:id"},
+			path:     "/api/customers",
+			wantTpl:  "",
+			wantOk:   false,
+		},
+		"dead-ending literal branch backtracks to the wildcard": {
+			patterns: []string{"/api/customers/:id", "/api/customers/new/confirm"},
+			path:     "/api/customers/new",
+			wantTpl:  "/api/customers/:id",
+			wantOk:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newPathMatcher(test.patterns)
+
+			gotTpl, gotOk := m.match(test.path)
+			if gotOk != test.wantOk {
+				t.Fatalf("got ok=%v, want %v", gotOk, test.wantOk)
+			}
+			if gotTpl != test.wantTpl {
+				t.Fatalf("got template %q, want %q", gotTpl, test.wantTpl)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/slok/go-http-metrics/pkg/middleware"
+)
+
+func newTestRecorder(t *testing.T) *recorder {
+	t.Helper()
+
+	rec := NewRecorder(Config{Registry: prometheus.NewRegistry()})
+	r, ok := rec.(*recorder)
+	if !ok {
+		t.Fatalf("NewRecorder didn't return a *recorder, got %T", rec)
+	}
+	return r
+}
+
+func serve(mdlw middleware.Middleware, handlerID string, status int) {
+	h := mdlw.Handler(handlerID, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMiddlewareDrivesRequestAndErrorCounters(t *testing.T) {
+	rec := newTestRecorder(t)
+	mdlw := middleware.New(middleware.Config{Recorder: rec})
+
+	serve(mdlw, "handler-ok", http.StatusOK)
+	serve(mdlw, "handler-ok", http.StatusOK)
+	serve(mdlw, "handler-fail", http.StatusInternalServerError)
+
+	gotOK := testutil.ToFloat64(rec.httpRequestsTotal.WithLabelValues("", "handler-ok", http.MethodGet, "200"))
+	if gotOK != 2 {
+		t.Fatalf("got %v requests for handler-ok, want 2", gotOK)
+	}
+
+	gotFail := testutil.ToFloat64(rec.httpRequestsTotal.WithLabelValues("", "handler-fail", http.MethodGet, "500"))
+	if gotFail != 1 {
+		t.Fatalf("got %v requests for handler-fail, want 1", gotFail)
+	}
+
+	gotOKErrors := testutil.ToFloat64(rec.httpRequestErrorsTotal.WithLabelValues("", "handler-ok", http.MethodGet, "200"))
+	if gotOKErrors != 0 {
+		t.Fatalf("a 200 response must not increment the error counter, got %v", gotOKErrors)
+	}
+
+	gotFailErrors := testutil.ToFloat64(rec.httpRequestErrorsTotal.WithLabelValues("", "handler-fail", http.MethodGet, "500"))
+	if gotFailErrors != 1 {
+		t.Fatalf("a 5xx response must increment the error counter, got %v", gotFailErrors)
+	}
+}
+
+func TestMiddlewareSignalErrorIncrementsErrorCounterOnNon5xx(t *testing.T) {
+	rec := newTestRecorder(t)
+	mdlw := middleware.New(middleware.Config{Recorder: rec})
+
+	h := mdlw.Handler("handler-business-error", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.SignalError(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	got := testutil.ToFloat64(rec.httpRequestErrorsTotal.WithLabelValues("", "handler-business-error", http.MethodGet, "200"))
+	if got != 1 {
+		t.Fatalf("SignalError must increment the error counter even on a 200 response, got %v", got)
+	}
+}
+
+func TestMiddlewareObservesRequestSizeWithoutStatusCodeLabel(t *testing.T) {
+	rec := newTestRecorder(t)
+	mdlw := middleware.New(middleware.Config{Recorder: rec})
+
+	h := mdlw.Handler("handler-ok", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.ContentLength = 1024
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	observer := rec.httpRequestSizeHistogram.WithLabelValues("", "handler-ok", http.MethodPost)
+	m, ok := observer.(prometheus.Metric)
+	if !ok {
+		t.Fatalf("observer %T doesn't implement prometheus.Metric", observer)
+	}
+
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		t.Fatalf("writing metric: %v", err)
+	}
+	if got := pb.GetHistogram().GetSampleCount(); got != 1 {
+		t.Fatalf("got %d request size samples, want 1", got)
+	}
+}
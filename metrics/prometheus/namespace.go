@@ -0,0 +1,50 @@
+package prometheus
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Namespace builds Prometheus collectors that share a namespace, a subsystem and a set of
+// constant labels, so callers don't have to repeat them on every metric they create.
+type Namespace struct {
+	prefix      string
+	subsystem   string
+	constLabels prometheus.Labels
+}
+
+// NewNamespace returns a Namespace that creates collectors pre-configured with prefix,
+// subsystem and constLabels. constLabels are attached to every collector created by the
+// Namespace without being part of its variable label set, this is useful to scope metrics
+// coming from different instances of the same service, eg: `version`, `git_commit`, `region`.
+func NewNamespace(prefix, subsystem string, constLabels map[string]string) *Namespace {
+	return &Namespace{
+		prefix:      prefix,
+		subsystem:   subsystem,
+		constLabels: constLabels,
+	}
+}
+
+// NewHistogramVec creates a *prometheus.HistogramVec using the Namespace prefix, subsystem
+// and const labels, overriding whatever was set on opts.
+func (n *Namespace) NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *prometheus.HistogramVec {
+	opts.Namespace = n.prefix
+	opts.Subsystem = n.subsystem
+	opts.ConstLabels = n.constLabels
+	return prometheus.NewHistogramVec(opts, labelNames)
+}
+
+// NewGaugeVec creates a *prometheus.GaugeVec using the Namespace prefix, subsystem and
+// const labels, overriding whatever was set on opts.
+func (n *Namespace) NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	opts.Namespace = n.prefix
+	opts.Subsystem = n.subsystem
+	opts.ConstLabels = n.constLabels
+	return prometheus.NewGaugeVec(opts, labelNames)
+}
+
+// NewCounterVec creates a *prometheus.CounterVec using the Namespace prefix, subsystem and
+// const labels, overriding whatever was set on opts.
+func (n *Namespace) NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	opts.Namespace = n.prefix
+	opts.Subsystem = n.subsystem
+	opts.ConstLabels = n.constLabels
+	return prometheus.NewCounterVec(opts, labelNames)
+}
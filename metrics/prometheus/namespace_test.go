@@ -0,0 +1,52 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func descString(t *testing.T, c prometheus.Collector) string {
+	t.Helper()
+
+	ch := make(chan *prometheus.Desc, 1)
+	c.Describe(ch)
+	close(ch)
+
+	desc := <-ch
+	if desc == nil {
+		t.Fatal("collector didn't describe itself")
+	}
+	return desc.String()
+}
+
+func TestNamespaceAppliesPrefixAndSubsystem(t *testing.T) {
+	ns := NewNamespace("myapp", "requests", nil)
+
+	hv := ns.NewHistogramVec(prometheus.HistogramOpts{Name: "latency_seconds", Help: "h"}, []string{"code"})
+
+	got := descString(t, hv)
+	if !strings.Contains(got, "myapp_requests_latency_seconds") {
+		t.Fatalf("descriptor %q doesn't contain the namespaced metric name", got)
+	}
+}
+
+func TestNamespaceAppliesConstLabelsToEveryCollectorType(t *testing.T) {
+	ns := NewNamespace("myapp", "http", map[string]string{"version": "1.2.3"})
+
+	collectors := map[string]prometheus.Collector{
+		"histogram": ns.NewHistogramVec(prometheus.HistogramOpts{Name: "h", Help: "h"}, nil),
+		"gauge":     ns.NewGaugeVec(prometheus.GaugeOpts{Name: "g", Help: "g"}, nil),
+		"counter":   ns.NewCounterVec(prometheus.CounterOpts{Name: "c", Help: "c"}, nil),
+	}
+
+	for name, c := range collectors {
+		t.Run(name, func(t *testing.T) {
+			got := descString(t, c)
+			if !strings.Contains(got, `version="1.2.3"`) {
+				t.Fatalf("descriptor %q doesn't carry the const label", got)
+			}
+		})
+	}
+}
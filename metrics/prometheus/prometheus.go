@@ -38,6 +38,30 @@ type Config struct {
 	// SizeBuckets are the buckets used by Prometheus for the HTTP response size metrics,
 	// by default uses a exponential buckets from 100B to 1GB.
 	SizeBuckets []float64
+	// RequestSizeBuckets are the buckets used by Prometheus for the HTTP request size metrics,
+	// by default uses 8 exponential buckets from 256B growing by a factor of 4 (up to 4MiB), as used by Caddy.
+	RequestSizeBuckets []float64
+	// Subsystem is the subsystem that will be set on the metrics, by default is `http`.
+	Subsystem string
+	// ConstLabels are labels that will be attached to every metric created by the recorder
+	// without being part of the variable label set, eg: `version`, `git_commit`, `region`.
+	ConstLabels map[string]string
+	// NativeHistogramBucketFactor, when set to a value greater than 1, switches the duration
+	// and size histograms to native (sparse bucket) histograms with this growth factor,
+	// as described in the Prometheus native histograms documentation. When set, the classic
+	// DurationBuckets/SizeBuckets/RequestSizeBuckets are ignored.
+	NativeHistogramBucketFactor float64
+	// NativeHistogramMaxBucketNumber caps the number of buckets a native histogram can use,
+	// only used when NativeHistogramBucketFactor is set.
+	NativeHistogramMaxBucketNumber uint32
+	// NativeHistogramMinResetDuration is the minimum time a native histogram waits before
+	// considering resetting its bucket counts, only used when NativeHistogramBucketFactor is set.
+	NativeHistogramMinResetDuration time.Duration
+	// LabelNormalizer, when set, is applied to the HTTPReqProperties before they are turned
+	// into labels in every HTTPReqProperties-based method (duration, request/response size,
+	// request and error counts), it can be used to curb label cardinality, eg: with
+	// metrics.StatusCodeClassNormalizer or metrics.HandlerIDTemplateNormalizer.
+	LabelNormalizer metrics.LabelNormalizer
 	// Registry is the registry that will be used by the recorder to store the metrics,
 	// if the default registry is not used then it will use the default one.
 	Registry prometheus.Registerer
@@ -52,6 +76,10 @@ func (c *Config) defaults() {
 		c.SizeBuckets = prometheus.ExponentialBuckets(100, 10, 8)
 	}
 
+	if len(c.RequestSizeBuckets) == 0 {
+		c.RequestSizeBuckets = prometheus.ExponentialBuckets(256, 4, 8)
+	}
+
 	if c.Registry == nil {
 		c.Registry = prometheus.DefaultRegisterer
 	}
@@ -71,13 +99,41 @@ func (c *Config) defaults() {
 	if c.ServiceLabel == "" {
 		c.ServiceLabel = "service"
 	}
+
+	if c.Subsystem == "" {
+		c.Subsystem = "http"
+	}
+}
+
+// histogramOpts builds the HistogramOpts for a latency/size metric, switching to a native
+// (sparse bucket) histogram instead of classic buckets when NativeHistogramBucketFactor is set.
+func (c Config) histogramOpts(name, help string, classicBuckets []float64) prometheus.HistogramOpts {
+	opts := prometheus.HistogramOpts{
+		Name:    name,
+		Help:    help,
+		Buckets: classicBuckets,
+	}
+
+	if c.NativeHistogramBucketFactor > 0 {
+		opts.Buckets = nil
+		opts.NativeHistogramBucketFactor = c.NativeHistogramBucketFactor
+		opts.NativeHistogramMaxBucketNumber = c.NativeHistogramMaxBucketNumber
+		opts.NativeHistogramMinResetDuration = c.NativeHistogramMinResetDuration
+		opts.NativeHistogramZeroThreshold = prometheus.DefNativeHistogramZeroThreshold
+	}
+
+	return opts
 }
 
 type recorder struct {
 	httpRequestDurHistogram   *prometheus.HistogramVec
+	httpRequestSizeHistogram  *prometheus.HistogramVec
 	httpResponseSizeHistogram *prometheus.HistogramVec
 	httpRequestsInflight      *prometheus.GaugeVec
+	httpRequestsTotal         *prometheus.CounterVec
+	httpRequestErrorsTotal    *prometheus.CounterVec
 	labels                    *Labels
+	labelNormalizer           metrics.LabelNormalizer
 }
 
 // NewRecorder returns a new metrics recorder that implements the recorder
@@ -90,42 +146,59 @@ func NewRecorder(cfg Config) metrics.Recorder {
 		customLabels = cfg.CustomLabels.GetLabels()
 	}
 
+	ns := NewNamespace(cfg.Prefix, cfg.Subsystem, cfg.ConstLabels)
+
 	r := &recorder{
-		httpRequestDurHistogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
-			Namespace: cfg.Prefix,
-			Subsystem: "http",
-			Name:      "request_duration_seconds",
-			Help:      "The latency of the HTTP requests.",
-			Buckets:   cfg.DurationBuckets,
+		httpRequestDurHistogram: ns.NewHistogramVec(
+			cfg.histogramOpts("request_duration_seconds", "The latency of the HTTP requests.", cfg.DurationBuckets),
+			append([]string{cfg.ServiceLabel, cfg.HandlerIDLabel, cfg.MethodLabel, cfg.StatusCodeLabel}, customLabels...)),
+
+		// Request size is observed before the handler runs, so the status code isn't known yet
+		// and is deliberately left out of this histogram's labels.
+		httpRequestSizeHistogram: ns.NewHistogramVec(
+			cfg.histogramOpts("request_size_bytes", "The size of the HTTP requests.", cfg.RequestSizeBuckets),
+			append([]string{cfg.ServiceLabel, cfg.HandlerIDLabel, cfg.MethodLabel}, customLabels...)),
+
+		httpResponseSizeHistogram: ns.NewHistogramVec(
+			cfg.histogramOpts("response_size_bytes", "The size of the HTTP responses.", cfg.SizeBuckets),
+			append([]string{cfg.ServiceLabel, cfg.HandlerIDLabel, cfg.MethodLabel, cfg.StatusCodeLabel}, customLabels...)),
+
+		httpRequestsInflight: ns.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "requests_inflight",
+			Help: "The number of inflight requests being handled at the same time.",
+		}, []string{cfg.ServiceLabel, cfg.HandlerIDLabel}),
+
+		httpRequestsTotal: ns.NewCounterVec(prometheus.CounterOpts{
+			Name: "requests_total",
+			Help: "The total number of HTTP requests handled.",
 		}, append([]string{cfg.ServiceLabel, cfg.HandlerIDLabel, cfg.MethodLabel, cfg.StatusCodeLabel}, customLabels...)),
 
-		httpResponseSizeHistogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
-			Namespace: cfg.Prefix,
-			Subsystem: "http",
-			Name:      "response_size_bytes",
-			Help:      "The size of the HTTP responses.",
-			Buckets:   cfg.SizeBuckets,
+		httpRequestErrorsTotal: ns.NewCounterVec(prometheus.CounterOpts{
+			Name: "request_errors_total",
+			Help: "The total number of HTTP requests that ended in a server error.",
 		}, append([]string{cfg.ServiceLabel, cfg.HandlerIDLabel, cfg.MethodLabel, cfg.StatusCodeLabel}, customLabels...)),
 
-		httpRequestsInflight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
-			Namespace: cfg.Prefix,
-			Subsystem: "http",
-			Name:      "requests_inflight",
-			Help:      "The number of inflight requests being handled at the same time.",
-		}, []string{cfg.ServiceLabel, cfg.HandlerIDLabel}),
-		labels: &cfg.Labels,
+		labels:          &cfg.Labels,
+		labelNormalizer: cfg.LabelNormalizer,
 	}
 
 	cfg.Registry.MustRegister(
 		r.httpRequestDurHistogram,
+		r.httpRequestSizeHistogram,
 		r.httpResponseSizeHistogram,
 		r.httpRequestsInflight,
+		r.httpRequestsTotal,
+		r.httpRequestErrorsTotal,
 	)
 
 	return r
 }
 
 func (r recorder) ObserveHTTPRequestDuration(_ context.Context, p metrics.HTTPReqProperties, duration time.Duration) {
+	if r.labelNormalizer != nil {
+		p = r.labelNormalizer(p)
+	}
+
 	// If custom labels are not defined then it is better to record metrics using WithLabelValues as reporting
 	// with With() has performance overhead due to using maps.
 	if r.labels.CustomLabels == nil {
@@ -143,6 +216,10 @@ func (r recorder) ObserveHTTPRequestDuration(_ context.Context, p metrics.HTTPRe
 }
 
 func (r recorder) ObserveHTTPResponseSize(_ context.Context, p metrics.HTTPReqProperties, sizeBytes int64) {
+	if r.labelNormalizer != nil {
+		p = r.labelNormalizer(p)
+	}
+
 	// If custom labels are not defined then it is better to record metrics using WithLabelValues as reporting
 	// with With() has performance overhead due to using maps.
 	if r.labels.CustomLabels == nil {
@@ -159,6 +236,69 @@ func (r recorder) ObserveHTTPResponseSize(_ context.Context, p metrics.HTTPReqPr
 	r.httpResponseSizeHistogram.With(labels).Observe(float64(sizeBytes))
 }
 
+func (r recorder) ObserveHTTPRequestSize(_ context.Context, p metrics.HTTPReqProperties, sizeBytes int64) {
+	if r.labelNormalizer != nil {
+		p = r.labelNormalizer(p)
+	}
+
+	// If custom labels are not defined then it is better to record metrics using WithLabelValues as reporting
+	// with With() has performance overhead due to using maps.
+	if r.labels.CustomLabels == nil {
+		r.httpRequestSizeHistogram.WithLabelValues(p.Service, p.ID, p.Method).Observe(float64(sizeBytes))
+		return
+	}
+
+	labels := prometheus.Labels{r.labels.ServiceLabel: p.Service, r.labels.HandlerIDLabel: p.ID,
+		r.labels.MethodLabel: p.Method}
+	customMetrics := r.labels.CustomLabels.Reporter(p.ID, p.Method, p.Body)
+	for _, label := range r.labels.CustomLabels.GetLabels() {
+		labels[label] = customMetrics[label]
+	}
+	r.httpRequestSizeHistogram.With(labels).Observe(float64(sizeBytes))
+}
+
 func (r recorder) AddInflightRequests(_ context.Context, p metrics.HTTPProperties, quantity int) {
 	r.httpRequestsInflight.WithLabelValues(p.Service, p.ID).Add(float64(quantity))
 }
+
+func (r recorder) AddRequestCount(_ context.Context, p metrics.HTTPReqProperties) {
+	if r.labelNormalizer != nil {
+		p = r.labelNormalizer(p)
+	}
+
+	// If custom labels are not defined then it is better to record metrics using WithLabelValues as reporting
+	// with With() has performance overhead due to using maps.
+	if r.labels.CustomLabels == nil {
+		r.httpRequestsTotal.WithLabelValues(p.Service, p.ID, p.Method, p.Code).Inc()
+		return
+	}
+
+	labels := prometheus.Labels{r.labels.ServiceLabel: p.Service, r.labels.HandlerIDLabel: p.ID,
+		r.labels.MethodLabel: p.Method, r.labels.StatusCodeLabel: p.Code}
+	customMetrics := r.labels.CustomLabels.Reporter(p.ID, p.Method, p.Body)
+	for _, label := range r.labels.CustomLabels.GetLabels() {
+		labels[label] = customMetrics[label]
+	}
+	r.httpRequestsTotal.With(labels).Inc()
+}
+
+func (r recorder) AddErrorCount(_ context.Context, p metrics.HTTPReqProperties) {
+	if r.labelNormalizer != nil {
+		p = r.labelNormalizer(p)
+	}
+
+	// If custom labels are not defined then it is better to record metrics using WithLabelValues as reporting
+	// with With() has performance overhead due to using maps.
+	if r.labels.CustomLabels == nil {
+		r.httpRequestErrorsTotal.WithLabelValues(p.Service, p.ID, p.Method, p.Code).Inc()
+		return
+	}
+
+	labels := prometheus.Labels{r.labels.ServiceLabel: p.Service, r.labels.HandlerIDLabel: p.ID,
+		r.labels.MethodLabel: p.Method, r.labels.StatusCodeLabel: p.Code}
+	customMetrics := r.labels.CustomLabels.Reporter(p.ID, p.Method, p.Body)
+	for _, label := range r.labels.CustomLabels.GetLabels() {
+		labels[label] = customMetrics[label]
+	}
+	r.httpRequestErrorsTotal.With(labels).Inc()
+}
@@ -0,0 +1,43 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigHistogramOptsClassicBuckets(t *testing.T) {
+	cfg := Config{}
+
+	buckets := []float64{1, 2, 3}
+	opts := cfg.histogramOpts("name", "help", buckets)
+
+	if len(opts.Buckets) != len(buckets) {
+		t.Fatalf("got %d buckets, want %d", len(opts.Buckets), len(buckets))
+	}
+	if opts.NativeHistogramBucketFactor != 0 {
+		t.Fatalf("native histogram fields must be unset by default, got factor %v", opts.NativeHistogramBucketFactor)
+	}
+}
+
+func TestConfigHistogramOptsNativeHistogram(t *testing.T) {
+	cfg := Config{
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: time.Hour,
+	}
+
+	opts := cfg.histogramOpts("name", "help", []float64{1, 2, 3})
+
+	if opts.Buckets != nil {
+		t.Fatalf("classic buckets must be ignored when NativeHistogramBucketFactor is set, got %v", opts.Buckets)
+	}
+	if opts.NativeHistogramBucketFactor != cfg.NativeHistogramBucketFactor {
+		t.Fatalf("got bucket factor %v, want %v", opts.NativeHistogramBucketFactor, cfg.NativeHistogramBucketFactor)
+	}
+	if opts.NativeHistogramMaxBucketNumber != cfg.NativeHistogramMaxBucketNumber {
+		t.Fatalf("got max bucket number %v, want %v", opts.NativeHistogramMaxBucketNumber, cfg.NativeHistogramMaxBucketNumber)
+	}
+	if opts.NativeHistogramMinResetDuration != cfg.NativeHistogramMinResetDuration {
+		t.Fatalf("got min reset duration %v, want %v", opts.NativeHistogramMinResetDuration, cfg.NativeHistogramMinResetDuration)
+	}
+}
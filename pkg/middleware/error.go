@@ -0,0 +1,27 @@
+package middleware
+
+import "context"
+
+// errorSignalKey is the context key Middleware uses to carry the per-request errorSignal.
+type errorSignalKey struct{}
+
+// errorSignal lets a handler mark the in-flight request as an error independently of the
+// response status code, eg: a handler that always replies 200 but encodes a business-logic
+// failure in the response body.
+type errorSignal struct {
+	failed bool
+}
+
+func withErrorSignal(ctx context.Context) (context.Context, *errorSignal) {
+	sig := &errorSignal{}
+	return context.WithValue(ctx, errorSignalKey{}, sig), sig
+}
+
+// SignalError marks the request being measured by Middleware as an error, causing
+// AddErrorCount to be invoked on the configured Recorder regardless of the response status
+// code. It's a no-op if ctx wasn't produced by a request going through Middleware.Handler.
+func SignalError(ctx context.Context) {
+	if sig, ok := ctx.Value(errorSignalKey{}).(*errorSignal); ok {
+		sig.failed = true
+	}
+}
@@ -0,0 +1,97 @@
+// Package middleware provides a framework-agnostic net/http middleware that
+// measures HTTP requests using a metrics.Recorder.
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/slok/go-http-metrics/metrics"
+)
+
+// Config is the configuration used to create a measuring Middleware.
+type Config struct {
+	// Recorder is the way the metrics will be recorded in the different backends.
+	Recorder metrics.Recorder
+	// Service is an optional identifier used to label every metric recorded by the middleware.
+	Service string
+	// DisableMeasureSize disables measuring the request and response sizes, by default sizes are measured.
+	DisableMeasureSize bool
+	// DisableMeasureInflight disables measuring the in-flight requests, by default inflight requests are measured.
+	DisableMeasureInflight bool
+}
+
+// Middleware is a net/http middleware that measures HTTP requests with a metrics.Recorder.
+type Middleware struct {
+	cfg Config
+}
+
+// New returns a new Middleware using cfg.
+func New(cfg Config) Middleware {
+	return Middleware{cfg: cfg}
+}
+
+// Handler wraps next measuring the requests it serves, identifying them with handlerID.
+// AddErrorCount is triggered both by a 5xx response status and by next calling SignalError
+// on the request's context.
+func (m Middleware) Handler(handlerID string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, errSig := withErrorSignal(r.Context())
+		r = r.WithContext(ctx)
+
+		if !m.cfg.DisableMeasureInflight {
+			p := metrics.HTTPProperties{Service: m.cfg.Service, ID: handlerID}
+			m.cfg.Recorder.AddInflightRequests(ctx, p, 1)
+			defer m.cfg.Recorder.AddInflightRequests(ctx, p, -1)
+		}
+
+		hprops := metrics.HTTPReqProperties{Service: m.cfg.Service, ID: handlerID, Method: r.Method}
+		if !m.cfg.DisableMeasureSize {
+			reqSize := r.ContentLength
+			if reqSize < 0 {
+				// ContentLength is -1 when it's unknown (chunked transfer encoding, HTTP/2
+				// without the header...), there's nothing to observe in that case.
+				reqSize = 0
+			}
+			m.cfg.Recorder.ObserveHTTPRequestSize(ctx, hprops, reqSize)
+		}
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(rw, r)
+		duration := time.Since(start)
+
+		hprops.Code = strconv.Itoa(rw.status)
+
+		m.cfg.Recorder.ObserveHTTPRequestDuration(ctx, hprops, duration)
+		m.cfg.Recorder.AddRequestCount(ctx, hprops)
+		if rw.status >= http.StatusInternalServerError || errSig.failed {
+			m.cfg.Recorder.AddErrorCount(ctx, hprops)
+		}
+
+		if !m.cfg.DisableMeasureSize {
+			m.cfg.Recorder.ObserveHTTPResponseSize(ctx, hprops, rw.size)
+		}
+	})
+}
+
+// responseWriter wraps a http.ResponseWriter to capture the status code and size written
+// so they can be reported to the metrics.Recorder once the handler has finished.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}